@@ -0,0 +1,142 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command grpcreplay-server fronts a gRPC recording over a real network
+// listener, replaying its recorded calls to whatever client connects —
+// including clients outside the Go process, such as grpcurl or a test
+// suite written in another language.
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/google/go-replayers/grpcreplay"
+)
+
+var (
+	addr        = flag.String("addr", ":0", "address to listen on")
+	format      = flag.String("format", "", `expected recording format ("binary", "text", "jsonl", ...); if set, the server refuses to start unless the recording actually matches it`)
+	faultConfig = flag.String("fault-config", "", "path to a JSON file describing fault/backoff injection (see backoffConfigFile below); if empty, calls are replayed with no injected faults")
+	tlsCert     = flag.String("tls-cert", "", "TLS certificate file; if set, the server terminates TLS, and -tls-key must also be given")
+	tlsKey      = flag.String("tls-key", "", "TLS private key file, paired with -tls-cert")
+)
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] recording-file\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0)); err != nil {
+		log.Fatalf("grpcreplay-server: %v", err)
+	}
+}
+
+func run(filename string) error {
+	if *format != "" {
+		got, err := grpcreplay.DetectFormat(filename)
+		if err != nil {
+			return fmt.Errorf("detecting format: %w", err)
+		}
+		if got != *format {
+			return fmt.Errorf("recording %s is in format %q, not %q", filename, got, *format)
+		}
+	}
+
+	var opts grpcreplay.ReplayerOptions
+	if *faultConfig != "" {
+		inj, err := loadFaultInjector(*faultConfig)
+		if err != nil {
+			return fmt.Errorf("loading -fault-config: %w", err)
+		}
+		opts.FaultInjector = inj
+	}
+
+	rep, err := grpcreplay.NewReplayer(filename, &opts)
+	if err != nil {
+		return fmt.Errorf("loading recording %s: %w", filename, err)
+	}
+	defer rep.Close()
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", *addr, err)
+	}
+	if *tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey)
+		if err != nil {
+			return fmt.Errorf("loading TLS key pair: %w", err)
+		}
+		lis = tls.NewListener(lis, &tls.Config{Certificates: []tls.Certificate{cert}})
+	}
+
+	log.Printf("replaying %s on %s", filename, lis.Addr())
+	return rep.Serve(lis)
+}
+
+// backoffConfigFile is the on-disk JSON shape of -fault-config. Its
+// fields mirror grpcreplay.BackoffConfig, with durations spelled out as
+// strings (e.g. "1s") since encoding/json has no native time.Duration
+// support, plus Seed, which is passed to NewBackoffFaultInjector.
+type backoffConfigFile struct {
+	Base     string  `json:"base"`
+	Factor   float64 `json:"factor"`
+	Jitter   float64 `json:"jitter"`
+	MaxDelay string  `json:"maxDelay"`
+	Failures int     `json:"failures"`
+	Seed     int64   `json:"seed"`
+}
+
+func loadFaultInjector(path string) (grpcreplay.FaultInjector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cf backoffConfigFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return nil, err
+	}
+	cfg := grpcreplay.BackoffConfig{
+		Factor:   cf.Factor,
+		Jitter:   cf.Jitter,
+		Failures: cf.Failures,
+	}
+	if cf.Base != "" {
+		d, err := time.ParseDuration(cf.Base)
+		if err != nil {
+			return nil, fmt.Errorf("base: %w", err)
+		}
+		cfg.Base = d
+	}
+	if cf.MaxDelay != "" {
+		d, err := time.ParseDuration(cf.MaxDelay)
+		if err != nil {
+			return nil, fmt.Errorf("maxDelay: %w", err)
+		}
+		cfg.MaxDelay = d
+	}
+	return grpcreplay.NewBackoffFaultInjector(cfg, cf.Seed), nil
+}
@@ -0,0 +1,267 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	pb "github.com/google/go-replayers/grpcreplay/proto/grpcreplay"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec implements one wire format for recording files: a magic prefix
+// that identifies it, plus how to write and read the header and entries
+// that follow it. Select a Codec for recording with
+// RecorderOptions.Format; a recording is read back with whichever
+// registered Codec's Magic matches its contents.
+//
+// The binary and text formats are built in. Register additional formats,
+// such as the jsonl format in this package, with RegisterCodec.
+type Codec interface {
+	// Magic is the byte sequence that begins every recording written in
+	// this format. It must be unique among registered codecs, and must
+	// not be a prefix of another registered codec's Magic (or vice
+	// versa).
+	Magic() []byte
+
+	// WriteHeader writes initial, the recording's opaque initial-state
+	// blob (see RecorderOptions.Initial), to w, preceded by Magic.
+	WriteHeader(w io.Writer, initial []byte) error
+
+	// ReadHeader reads and returns the initial-state blob written by
+	// WriteHeader, including consuming the leading Magic.
+	ReadHeader(r io.Reader) ([]byte, error)
+
+	// WriteEntry writes a single recorded event to w.
+	WriteEntry(w io.Writer, e *pb.Entry) error
+
+	// ReadEntry reads a single recorded event from r. It returns io.EOF
+	// when r holds no further entries.
+	ReadEntry(r io.Reader) (*pb.Entry, error)
+}
+
+var (
+	codecMu       sync.Mutex
+	codecRegistry = map[string]Codec{}
+)
+
+// RegisterCodec makes c available for selection by name via
+// RecorderOptions.Format, and for automatic detection by magic prefix
+// when a Replayer reads a recording. It is typically called from an
+// init function. Registering a codec under a name already in use
+// replaces the previous one.
+func RegisterCodec(name string, c Codec) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	codecRegistry[name] = c
+}
+
+// codecWriter adapts a Codec to the writer interface for Recorders
+// configured with RecorderOptions.Format naming a registered Codec other
+// than the built-in "binary" and "text" formats.
+type codecWriter struct {
+	c Codec
+	w io.Writer
+}
+
+func (cw *codecWriter) writeHeader(initial []byte) error {
+	return cw.c.WriteHeader(cw.w, initial)
+}
+
+func (cw *codecWriter) writeEntry(e *entry) error {
+	pe, err := entryToProto(e)
+	if err != nil {
+		return err
+	}
+	return cw.c.WriteEntry(cw.w, pe)
+}
+
+// codecReader is codecWriter's counterpart for reading.
+type codecReader struct {
+	c    Codec
+	r    io.Reader
+	name string
+}
+
+func (cr *codecReader) readHeader() ([]byte, error) {
+	b, err := cr.c.ReadHeader(cr.r)
+	if err != nil {
+		return nil, fmt.Errorf("grpcreplay: reading header in %s: %w", cr.name, err)
+	}
+	return b, nil
+}
+
+func (cr *codecReader) readEntry() (*entry, error) {
+	pe, err := cr.c.ReadEntry(cr.r)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return protoToEntry(pe)
+}
+
+// binaryCodec implements Codec for the default binary format: the magic
+// string, then a sequence of length-prefixed protocol buffer messages —
+// first the header, then one Entry per recorded event.
+type binaryCodec struct{}
+
+func (binaryCodec) Magic() []byte { return []byte(binaryMagic) }
+
+func (binaryCodec) WriteHeader(w io.Writer, initial []byte) error {
+	if _, err := io.WriteString(w, binaryMagic); err != nil {
+		return err
+	}
+	return writeBytes(w, initial)
+}
+
+func (binaryCodec) ReadHeader(r io.Reader) ([]byte, error) {
+	m := make([]byte, len(binaryMagic))
+	if _, err := io.ReadFull(r, m); err != nil {
+		return nil, err
+	}
+	if string(m) != binaryMagic {
+		return nil, fmt.Errorf("grpcreplay: bad binary magic %q", m)
+	}
+	return readBytes(r)
+}
+
+func (binaryCodec) WriteEntry(w io.Writer, e *pb.Entry) error {
+	b, err := proto.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writeBytes(w, b)
+}
+
+func (binaryCodec) ReadEntry(r io.Reader) (*pb.Entry, error) {
+	b, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	var pe pb.Entry
+	if err := proto.Unmarshal(b, &pe); err != nil {
+		return nil, err
+	}
+	return &pe, nil
+}
+
+// textCodec implements Codec for the human-readable text format: each
+// entry is a line describing its kind, method, ref index and error flag, followed by
+// a line with the entry's message, base64-encoded, followed by a blank
+// line. See textWriter/textReader for why the format is line-oriented.
+type textCodec struct{}
+
+func (textCodec) Magic() []byte { return []byte(textMagic) }
+
+func (textCodec) WriteHeader(w io.Writer, initial []byte) error {
+	_, err := fmt.Fprintf(w, "%s\n%s\n", textMagic, base64.StdEncoding.EncodeToString(initial))
+	return err
+}
+
+func (textCodec) ReadHeader(r io.Reader) ([]byte, error) {
+	br := asBufReader(r)
+	line, err := readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	if line != textMagic {
+		return nil, fmt.Errorf("grpcreplay: bad text magic %q", line)
+	}
+	line, err = readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(line)
+}
+
+func (textCodec) WriteEntry(w io.Writer, pe *pb.Entry) error {
+	_, err := fmt.Fprintf(w, "%s %s %d %v\n%s\n\n",
+		pe.GetKind(), pe.GetMethod(), pe.GetRefIndex(), pe.GetIsError(),
+		base64.StdEncoding.EncodeToString(pe.GetMessage()))
+	return err
+}
+
+func (textCodec) ReadEntry(r io.Reader) (*pb.Entry, error) {
+	br := asBufReader(r)
+	line, err := readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, io.EOF
+	}
+	parts := strings.SplitN(line, " ", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("grpcreplay: malformed entry line %q", line)
+	}
+	kind, ok := pb.Entry_Kind_value[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("grpcreplay: unknown entry kind %q", parts[0])
+	}
+	refIndex, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	isError, err := strconv.ParseBool(parts[3])
+	if err != nil {
+		return nil, err
+	}
+	dataLine, err := readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(dataLine)
+	if err != nil {
+		return nil, err
+	}
+	// Consume the blank separator line between entries, if present.
+	if _, err := readTextLine(br); err != nil {
+		return nil, err
+	}
+	return &pb.Entry{
+		Kind:     pb.Entry_Kind(kind),
+		Method:   parts[1],
+		RefIndex: int32(refIndex),
+		Message:  data,
+		IsError:  isError,
+	}, nil
+}
+
+// asBufReader returns r as a *bufio.Reader, reusing it directly if it
+// already is one. Line-oriented codecs rely on the caller passing the
+// same *bufio.Reader to every call so that look-ahead isn't discarded
+// between calls.
+func asBufReader(r io.Reader) *bufio.Reader {
+	if br, ok := r.(*bufio.Reader); ok {
+		return br
+	}
+	return bufio.NewReader(r)
+}
+
+func readTextLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\n"), nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcreplay supports the capture and replay of gRPC calls. Capturing
+// is usually done in production; replaying is usually done in a test.
+//
+// To capture a gRPC client's calls, wrap its connection with a Recorder:
+//
+//	rec, err := grpcreplay.NewRecorder("service.replay", nil)
+//	...
+//	conn, err := grpc.Dial(serverAddr, append(rec.DialOptions(), grpc.WithInsecure())...)
+//	...
+//	client := pb.NewServiceClient(conn)
+//	// Use client as usual.
+//	...
+//	err = rec.Close() // call this when done using the client
+//
+// To replay those calls in a test, construct a Replayer and get a client
+// connection from it:
+//
+//	rep, err := grpcreplay.NewReplayer("service.replay", nil)
+//	...
+//	defer rep.Close()
+//	conn, err := rep.Connection()
+//	...
+//	client := pb.NewServiceClient(conn)
+//	// Use client as usual.
+package grpcreplay
@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+// FaultInjector decides, for the attempt-th call (0 for the first) to
+// method, whether the Replayer should inject a fault instead of
+// delivering the recorded response or creating the recorded stream. It
+// is called once per incoming call, before that call is matched against
+// the recording.
+//
+// A non-nil returned *gstatus.Status is sent to the client as the RPC's
+// error, and the recorded call is left in place to be matched by a
+// later attempt. If delay is positive, the Replayer sleeps for that long
+// before doing anything else, whether or not an error is also returned.
+// Returning (0, nil) tells the Replayer to proceed as usual.
+type FaultInjector func(method string, attempt int) (delay time.Duration, err *gstatus.Status)
+
+// BackoffConfig parameterizes NewBackoffFaultInjector's delay and failure
+// pattern.
+type BackoffConfig struct {
+	// Base is the delay before the first retry. Defaults to 1 second.
+	Base time.Duration
+	// Factor is the multiplier applied to the delay for each subsequent
+	// attempt. Defaults to 1.6.
+	Factor float64
+	// Jitter is the fraction by which the computed delay is randomly
+	// perturbed, up or down. Defaults to 0.2.
+	Jitter float64
+	// MaxDelay caps the computed delay. Defaults to 120 seconds.
+	MaxDelay time.Duration
+	// Failures is the number of times each method fails, with
+	// codes.Unavailable, before NewBackoffFaultInjector lets the real
+	// recorded call through. Zero means the injector only adds delays
+	// and never fails a call.
+	Failures int
+}
+
+// NewBackoffFaultInjector returns a FaultInjector that mimics the gRPC
+// connection backoff spec: it computes
+//
+//	delay = min(Base*Factor^attempt, MaxDelay) * (1 ± Jitter)
+//
+// and, for the first Failures attempts at each method, returns that
+// delay alongside a codes.Unavailable error; after that it returns the
+// delay with no error, letting the recorded response through. seed makes
+// the jitter reproducible across runs.
+func NewBackoffFaultInjector(cfg BackoffConfig, seed int64) FaultInjector {
+	if cfg.Base <= 0 {
+		cfg.Base = time.Second
+	}
+	if cfg.Factor <= 0 {
+		cfg.Factor = 1.6
+	}
+	if cfg.Jitter <= 0 {
+		cfg.Jitter = 0.2
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 120 * time.Second
+	}
+
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(seed))
+
+	return func(method string, attempt int) (time.Duration, *gstatus.Status) {
+		mu.Lock()
+		jitter := 1 + cfg.Jitter*(2*rng.Float64()-1)
+		mu.Unlock()
+
+		delay := time.Duration(float64(cfg.Base) * math.Pow(cfg.Factor, float64(attempt)))
+		if delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+		delay = time.Duration(float64(delay) * jitter)
+
+		if attempt >= cfg.Failures {
+			return delay, nil
+		}
+		return delay, gstatus.New(codes.Unavailable,
+			fmt.Sprintf("grpcreplay: injected failure %d/%d for %s", attempt+1, cfg.Failures, method))
+	}
+}
+
+// expectRetriesInjector implements ReplayerOptions.ExpectRetries as a
+// FaultInjector with no delay, used when ExpectRetries is set but
+// FaultInjector is not.
+func expectRetriesInjector(n int) FaultInjector {
+	return func(method string, attempt int) (time.Duration, *gstatus.Status) {
+		if attempt >= n {
+			return 0, nil
+		}
+		return 0, gstatus.New(codes.Unavailable,
+			fmt.Sprintf("grpcreplay: tolerating retry %d/%d for %s", attempt+1, n, method))
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ipb "github.com/google/go-replayers/grpcreplay/proto/intstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectRetries(t *testing.T) {
+	buf := record(t, "binary", testService)
+	rep, err := NewReplayerReader(buf, &ReplayerOptions{ExpectRetries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rep.Close()
+	conn, err := rep.Connection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := ipb.NewIntStoreClient(conn)
+	ctx := context.Background()
+	item := &ipb.Item{Name: "a", Value: 1}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Set(ctx, item); status.Code(err) != codes.Unavailable {
+			t.Fatalf("attempt %d: got %v, want Unavailable", i, err)
+		}
+	}
+	res, err := client.Set(ctx, item)
+	if err != nil {
+		t.Fatalf("final attempt: %v", err)
+	}
+	if res.PrevValue != 0 {
+		t.Errorf("got %d, want 0", res.PrevValue)
+	}
+}
+
+func TestExpectRetriesRepeatedMethod(t *testing.T) {
+	buf := record(t, "binary", func(t *testing.T, conn *grpc.ClientConn) {
+		client := ipb.NewIntStoreClient(conn)
+		ctx := context.Background()
+		if _, err := client.Set(ctx, &ipb.Item{Name: "a", Value: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Set(ctx, &ipb.Item{Name: "a", Value: 2}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	rep, err := NewReplayerReader(buf, &ReplayerOptions{ExpectRetries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rep.Close()
+	conn, err := rep.Connection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := ipb.NewIntStoreClient(conn)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Set(ctx, &ipb.Item{Name: "a", Value: 1}); status.Code(err) != codes.Unavailable {
+			t.Fatalf("first call, attempt %d: got %v, want Unavailable", i, err)
+		}
+	}
+	if _, err := client.Set(ctx, &ipb.Item{Name: "a", Value: 1}); err != nil {
+		t.Fatalf("first call, final attempt: %v", err)
+	}
+
+	// The second, independent call to the same method must not be made
+	// to pay for the first call's retries: it should succeed on its
+	// first attempt.
+	if _, err := client.Set(ctx, &ipb.Item{Name: "a", Value: 2}); err != nil {
+		t.Fatalf("second call: got %v, want success on first attempt", err)
+	}
+}
+
+func TestBackoffFaultInjector(t *testing.T) {
+	inj := NewBackoffFaultInjector(BackoffConfig{
+		Base:     10 * time.Millisecond,
+		Factor:   2,
+		Jitter:   0,
+		MaxDelay: time.Second,
+		Failures: 2,
+	}, 1)
+
+	for attempt, wantCode := range []codes.Code{codes.Unavailable, codes.Unavailable, codes.OK} {
+		delay, st := inj("/x/Y", attempt)
+		if delay <= 0 {
+			t.Errorf("attempt %d: got non-positive delay %v", attempt, delay)
+		}
+		gotCode := codes.OK
+		if st != nil {
+			gotCode = st.Code()
+		}
+		if gotCode != wantCode {
+			t.Errorf("attempt %d: got code %v, want %v", attempt, gotCode, wantCode)
+		}
+	}
+}
+
+func TestBackoffFaultInjectorDeterministic(t *testing.T) {
+	cfg := BackoffConfig{Failures: 3}
+	inj1 := NewBackoffFaultInjector(cfg, 42)
+	inj2 := NewBackoffFaultInjector(cfg, 42)
+	for attempt := 0; attempt < 5; attempt++ {
+		d1, st1 := inj1("/x/Y", attempt)
+		d2, st2 := inj2("/x/Y", attempt)
+		if d1 != d2 {
+			t.Errorf("attempt %d: delays differ: %v vs %v", attempt, d1, d2)
+		}
+		if (st1 == nil) != (st2 == nil) {
+			t.Errorf("attempt %d: statuses differ: %v vs %v", attempt, st1, st2)
+		}
+	}
+}
@@ -0,0 +1,1203 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/google/go-replayers/grpcreplay/proto/grpcreplay"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// reflectionServiceName is the fully qualified name of the gRPC server
+// reflection service. Calls to it are never recorded: the Recorder issues
+// its own reflection calls to capture descriptors (see ensureReflection),
+// and those must pass through undisturbed rather than becoming entries in
+// the recording.
+const reflectionServiceName = "/grpc.reflection.v1alpha.ServerReflection/"
+
+// bufSize is the buffer size used for the in-process listener that backs a
+// Replayer's connection.
+const bufSize = 1 << 20
+
+// binaryMagic begins the header of a file in the binary format.
+const binaryMagic = "RPCReplay"
+
+// textMagic begins the header of a file in the text format.
+const textMagic = binaryMagic + "-text"
+
+// message is either a successfully received/sent proto.Message, or the
+// error that was received/sent instead (including the io.EOF sentinel
+// that signals the end of a stream).
+type message struct {
+	msg proto.Message
+	err error
+}
+
+func (m message) equal(m2 message) bool {
+	if (m.err == nil) != (m2.err == nil) {
+		return false
+	}
+	if m.err != nil {
+		if m.err == io.EOF || m2.err == io.EOF {
+			return m.err == m2.err
+		}
+		s1, _ := gstatus.FromError(m.err)
+		s2, _ := gstatus.FromError(m2.err)
+		return s1.Code() == s2.Code() && s1.Message() == s2.Message()
+	}
+	return proto.Equal(m.msg, m2.msg)
+}
+
+// entry is a single recorded gRPC event.
+type entry struct {
+	kind     pb.Entry_Kind
+	method   string
+	msg      message
+	refIndex int32 // 1-based index of the entry this one refers to, if any
+}
+
+func (e *entry) equal(e2 *entry) bool {
+	if e == nil || e2 == nil {
+		return e == e2
+	}
+	return e.kind == e2.kind && e.method == e2.method && e.refIndex == e2.refIndex && e.msg.equal(e2.msg)
+}
+
+// writer writes a recording's header and entries, in whatever format is in
+// use.
+type writer interface {
+	writeHeader(initial []byte) error
+	writeEntry(e *entry) error
+}
+
+// reader reads a recording's header and entries. It returns (nil, nil) from
+// readEntry to signal a clean end of the recording.
+type reader interface {
+	readHeader() ([]byte, error)
+	readEntry() (*entry, error)
+}
+
+// newReader returns a reader for the format detected from the magic bytes
+// at the start of r. name is used only to annotate error messages (it is
+// typically the name of the file r reads from).
+func newReader(r io.Reader, name string) (reader, error) {
+	br := bufio.NewReader(r)
+	format, c, err := detectFormat(br)
+	if err != nil {
+		return nil, fmt.Errorf("grpcreplay: unrecognized header in %s", name)
+	}
+	switch format {
+	case "text":
+		return newTextReader(br, name), nil
+	case "binary":
+		return &binaryReader{br}, nil
+	default:
+		return &codecReader{c: c, r: br, name: name}, nil
+	}
+}
+
+// DetectFormat returns the name of the recording format filename was
+// written in: "binary", "text", or the name a Codec was passed to
+// RegisterCodec under. It reads only enough of the file to recognize its
+// magic prefix.
+func DetectFormat(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	format, _, err := detectFormat(bufio.NewReader(f))
+	if err != nil {
+		return "", fmt.Errorf("grpcreplay: unrecognized header in %s", filename)
+	}
+	return format, nil
+}
+
+// detectFormat peeks at br to determine which registered format its
+// contents start with, returning that format's name and, for a format
+// registered via RegisterCodec, its Codec ("binary" and "text" have none
+// to return; they're handled directly by newReader).
+//
+// binaryMagic is a prefix of the text and jsonl magics (they extend it
+// with a suffix), so the text magic and the registered codecs' magics
+// are tried first and the plain binary check last; otherwise it would
+// shadow them. Registered codecs are tried in no particular order.
+func detectFormat(br *bufio.Reader) (name string, c Codec, err error) {
+	codecMu.Lock()
+	registered := make(map[string]Codec, len(codecRegistry))
+	for n, rc := range codecRegistry {
+		registered[n] = rc
+	}
+	codecMu.Unlock()
+
+	peekLen := len(textMagic)
+	for _, rc := range registered {
+		if n := len(rc.Magic()); n > peekLen {
+			peekLen = n
+		}
+	}
+
+	lookahead, perr := br.Peek(peekLen)
+	if perr != nil && perr != io.EOF && perr != bufio.ErrBufferFull {
+		return "", nil, perr
+	}
+	if bytes.HasPrefix(lookahead, []byte(textMagic)) {
+		return "text", nil, nil
+	}
+	for n, rc := range registered {
+		if bytes.HasPrefix(lookahead, rc.Magic()) {
+			return n, rc, nil
+		}
+	}
+	if bytes.HasPrefix(lookahead, []byte(binaryMagic)) {
+		return "binary", nil, nil
+	}
+	return "", nil, fmt.Errorf("grpcreplay: unrecognized header")
+}
+
+// The binary format writes the magic string, then a sequence of
+// length-prefixed protocol buffer messages: first the header, then one
+// Entry per recorded event. It is implemented by binaryCodec; binaryWriter
+// and binaryReader just adapt that Codec to the writer/reader interfaces.
+
+type binaryWriter struct {
+	w io.Writer
+}
+
+func (w *binaryWriter) writeHeader(initial []byte) error {
+	return binaryCodec{}.WriteHeader(w.w, initial)
+}
+
+func (w *binaryWriter) writeEntry(e *entry) error {
+	pe, err := entryToProto(e)
+	if err != nil {
+		return err
+	}
+	return binaryCodec{}.WriteEntry(w.w, pe)
+}
+
+type binaryReader struct {
+	r io.Reader
+}
+
+func (r *binaryReader) readHeader() ([]byte, error) {
+	return binaryCodec{}.ReadHeader(r.r)
+}
+
+func (r *binaryReader) readEntry() (*entry, error) {
+	pe, err := binaryCodec{}.ReadEntry(r.r)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return protoToEntry(pe)
+}
+
+func writeBytes(w io.Writer, b []byte) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(b)))
+	if _, err := w.Write(buf[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readBytes(r io.Reader) ([]byte, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		return nil, fmt.Errorf("grpcreplay: reader must implement io.ByteReader")
+	}
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// The text format is line-oriented, so that recordings can be diffed and
+// hand-edited. The header is the magic string followed by the initial
+// state, base64-encoded. Each entry is a line describing the entry's kind,
+// method, ref index and error flag, followed by a line with the entry's message,
+// base64-encoded, followed by a blank line. It is implemented by
+// textCodec; textWriter and textReader just adapt that Codec to the
+// writer/reader interfaces.
+
+type textWriter struct {
+	w io.Writer
+}
+
+func (w *textWriter) writeHeader(initial []byte) error {
+	return textCodec{}.WriteHeader(w.w, initial)
+}
+
+func (w *textWriter) writeEntry(e *entry) error {
+	pe, err := entryToProto(e)
+	if err != nil {
+		return err
+	}
+	return textCodec{}.WriteEntry(w.w, pe)
+}
+
+type textReader struct {
+	r    *bufio.Reader
+	name string
+}
+
+func newTextReader(r io.Reader, name string) *textReader {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &textReader{r: br, name: name}
+}
+
+func (r *textReader) readHeader() ([]byte, error) {
+	return textCodec{}.ReadHeader(r.r)
+}
+
+func (r *textReader) readEntry() (*entry, error) {
+	pe, err := textCodec{}.ReadEntry(r.r)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return protoToEntry(pe)
+}
+
+// entryToProto converts an entry to its wire representation, wrapping its
+// message in an Any so that readers with no knowledge of the RPC's proto
+// types can still reconstruct it (see protoToEntry). A CREATE_STREAM entry
+// carries neither a message nor an error, so that case is left as a
+// zero-length Message rather than passed to marshalAny, which rejects nil.
+func entryToProto(e *entry) (*pb.Entry, error) {
+	isError := e.msg.err != nil
+	var data []byte
+	var err error
+	switch {
+	case isError:
+		data = marshalErr(e.msg.err)
+	case e.msg.msg != nil:
+		data, err = marshalAny(e.msg.msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &pb.Entry{
+		Kind:     e.kind,
+		Method:   e.method,
+		RefIndex: e.refIndex,
+		Message:  data,
+		IsError:  isError,
+	}, nil
+}
+
+func protoToEntry(pe *pb.Entry) (*entry, error) {
+	var m message
+	var err error
+	switch {
+	case pe.GetIsError():
+		m.err, err = unmarshalErr(pe.GetMessage())
+	case len(pe.GetMessage()) > 0:
+		m.msg, err = unmarshalAny(pe.GetMessage())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry{
+		kind:     pe.GetKind(),
+		method:   pe.GetMethod(),
+		msg:      m,
+		refIndex: pe.GetRefIndex(),
+	}, nil
+}
+
+func marshalAny(m proto.Message) ([]byte, error) {
+	any, err := anypb.New(m)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(any)
+}
+
+func unmarshalAny(data []byte) (proto.Message, error) {
+	var any anypb.Any
+	if err := proto.Unmarshal(data, &any); err != nil {
+		return nil, err
+	}
+	return any.UnmarshalNew()
+}
+
+// eofSentinel marks a recorded io.EOF, which is not itself a gRPC status
+// error but is a common "error" value for stream Recvs.
+const eofSentinel = "EOF"
+
+func marshalErr(err error) []byte {
+	if err == io.EOF {
+		return []byte(eofSentinel)
+	}
+	st, _ := gstatus.FromError(err)
+	b, merr := proto.Marshal(st.Proto())
+	if merr != nil {
+		// st.Proto() always marshals cleanly; this should never happen.
+		panic(merr)
+	}
+	return b
+}
+
+func unmarshalErr(data []byte) (error, error) {
+	if string(data) == eofSentinel {
+		return io.EOF, nil
+	}
+	var sp spb.Status
+	if err := proto.Unmarshal(data, &sp); err != nil {
+		return nil, err
+	}
+	return gstatus.ErrorProto(&sp), nil
+}
+
+// A Recorder records RPCs made through its connection and writes them to an
+// io.Writer, for later replay by a Replayer.
+type Recorder struct {
+	mu                sync.Mutex
+	w                 writer
+	opts              RecorderOptions
+	initial           []byte
+	fileDescriptorSet []byte
+	headerWritten     bool
+	next              int32
+	err               error
+	close             func() error
+
+	reflectOnce sync.Once
+}
+
+// RecorderOptions configures a Recorder.
+type RecorderOptions struct {
+	// Initial state to be retrieved via Replayer.Initial.
+	Initial []byte
+
+	// BeforeWrite, if non-nil, is called with the method and message
+	// about to be written to the recording. It is given a copy of the
+	// message, so modifying it does not change what is actually sent
+	// or received on the wire. Returning an error fails the RPC.
+	BeforeWrite func(method string, msg proto.Message) error
+
+	// Text, if true, selects the human-readable recording format
+	// instead of the default, more compact binary format. Deprecated: use
+	// Format: "text" instead; Text is ignored if Format is non-empty.
+	Text bool
+
+	// Format selects the recording's Codec by name: "binary" (the
+	// default), "text", or the name of a Codec passed to RegisterCodec
+	// (for example "jsonl"). If empty, Text determines whether "binary"
+	// or "text" is used.
+	Format string
+
+	// CaptureReflection, if true, has the Recorder use gRPC server
+	// reflection against the target, on the first RPC made through the
+	// connection, to fetch the FileDescriptorProtos for every service it
+	// advertises and embed them as the recording's first entry. A
+	// Replayer reading the recording can then serve its own reflection
+	// service (see Replayer.RegisterReflection), letting tools like
+	// grpcurl introspect it with no compiled stubs of their own. It does
+	// not change how other entries are encoded: decoding a recording's
+	// messages still requires the original generated Go stubs to be
+	// linked into the process.
+	//
+	// Capture is best-effort: if the target does not implement the
+	// reflection service, or the attempt otherwise fails, recording
+	// proceeds normally without embedded descriptors.
+	CaptureReflection bool
+}
+
+// NewRecorder creates a Recorder that writes the recording to filename.
+func NewRecorder(filename string, opts *RecorderOptions) (*Recorder, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := NewRecorderWriter(f, opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rec.close = f.Close
+	return rec, nil
+}
+
+// NewRecorderWriter creates a Recorder that writes the recording to w.
+func NewRecorderWriter(w io.Writer, opts *RecorderOptions) (*Recorder, error) {
+	if opts == nil {
+		opts = &RecorderOptions{}
+	}
+	format := opts.Format
+	if format == "" {
+		if opts.Text {
+			format = "text"
+		} else {
+			format = "binary"
+		}
+	}
+	var wr writer
+	switch format {
+	case "binary":
+		wr = &binaryWriter{w}
+	case "text":
+		wr = &textWriter{w}
+	default:
+		codecMu.Lock()
+		c, ok := codecRegistry[format]
+		codecMu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("grpcreplay: unknown recording format %q", format)
+		}
+		wr = &codecWriter{c: c, w: w}
+	}
+	return &Recorder{w: wr, opts: *opts, initial: opts.Initial}, nil
+}
+
+// DialOptions returns the options that must be passed to grpc.Dial (or
+// grpc.DialContext) to record calls made on the resulting connection.
+func (r *Recorder) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(r.interceptUnary),
+		grpc.WithStreamInterceptor(r.interceptStream),
+	}
+}
+
+// SetInitial sets the initial state saved with the recording, overriding
+// any value set in RecorderOptions. It must be called before any RPCs are
+// made on a connection using the Recorder's DialOptions.
+func (r *Recorder) SetInitial(initial []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.initial = initial
+}
+
+// Close closes the Recorder, flushing any unwritten data and closing the
+// underlying file, if one was opened by NewRecorder.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	err := r.writeHeaderLocked()
+	r.mu.Unlock()
+	if r.close != nil {
+		if cerr := r.close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// writeHeaderLocked writes the recording's header, followed by a
+// FILE_DESCRIPTOR_SET entry if reflection was captured, the first time
+// either writeEntry or Close is called. r.mu must be held.
+func (r *Recorder) writeHeaderLocked() error {
+	if r.headerWritten {
+		return nil
+	}
+	r.headerWritten = true
+	if err := r.w.writeHeader(r.initial); err != nil {
+		return err
+	}
+	if len(r.fileDescriptorSet) == 0 {
+		return nil
+	}
+	var fdSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(r.fileDescriptorSet, &fdSet); err != nil {
+		return err
+	}
+	r.next++
+	return r.w.writeEntry(&entry{kind: pb.Entry_FILE_DESCRIPTOR_SET, msg: message{msg: &fdSet}})
+}
+
+// ensureReflection captures reflection descriptors from cc's target at
+// most once, the first time it's called. It must run before the header is
+// written, which happens on the first call to writeEntry.
+func (r *Recorder) ensureReflection(cc *grpc.ClientConn) {
+	if !r.opts.CaptureReflection {
+		return
+	}
+	r.reflectOnce.Do(func() {
+		fds, err := fetchFileDescriptorSet(cc)
+		if err != nil {
+			// Best-effort: proceed without embedded descriptors.
+			return
+		}
+		r.mu.Lock()
+		r.fileDescriptorSet = fds
+		r.mu.Unlock()
+	})
+}
+
+// fetchFileDescriptorSet uses gRPC server reflection over cc to fetch a
+// FileDescriptorSet covering every service the target advertises, and
+// returns it marshaled. Descriptors are captured for every advertised
+// service, rather than only the methods a recording goes on to exercise,
+// because the result must be ready before the first entry (and so the
+// header) is written.
+func fetchFileDescriptorSet(cc *grpc.ClientConn) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := rpb.NewServerReflectionClient(cc).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	}); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	list := resp.GetListServicesResponse()
+	if list == nil {
+		return nil, fmt.Errorf("grpcreplay: unexpected reflection response listing services: %v", resp)
+	}
+
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+	for _, svc := range list.GetService() {
+		if err := stream.Send(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: svc.GetName()},
+		}); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		fdr := resp.GetFileDescriptorResponse()
+		if fdr == nil {
+			continue
+		}
+		for _, b := range fdr.GetFileDescriptorProto() {
+			var fdp descriptorpb.FileDescriptorProto
+			if err := proto.Unmarshal(b, &fdp); err != nil {
+				return nil, err
+			}
+			files[fdp.GetName()] = &fdp
+		}
+	}
+	fdSet := &descriptorpb.FileDescriptorSet{}
+	for _, fdp := range files {
+		fdSet.File = append(fdSet.File, fdp)
+	}
+	return proto.Marshal(fdSet)
+}
+
+// applyBeforeWrite returns a clone of m with opts.BeforeWrite applied, so
+// that the original message that's actually sent or received is never
+// mutated.
+func (r *Recorder) applyBeforeWrite(method string, m proto.Message) (proto.Message, error) {
+	if r.opts.BeforeWrite == nil || m == nil {
+		return m, nil
+	}
+	clone := proto.Clone(m)
+	if err := r.opts.BeforeWrite(method, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// writeEntry writes e and returns the 1-based index assigned to it.
+func (r *Recorder) writeEntry(e *entry) (int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	if err := r.writeHeaderLocked(); err != nil {
+		r.err = err
+		return 0, err
+	}
+	r.next++
+	idx := r.next
+	if err := r.w.writeEntry(e); err != nil {
+		r.err = err
+		return 0, err
+	}
+	return idx, nil
+}
+
+func (r *Recorder) interceptUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if strings.HasPrefix(method, reflectionServiceName) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	r.ensureReflection(cc)
+	reqMsg, ok := req.(proto.Message)
+	if !ok {
+		return fmt.Errorf("grpcreplay: request of type %T is not a proto.Message", req)
+	}
+	toWrite, err := r.applyBeforeWrite(method, reqMsg)
+	if err != nil {
+		return err
+	}
+	idx, err := r.writeEntry(&entry{kind: pb.Entry_REQUEST, method: method, msg: message{msg: toWrite}})
+	if err != nil {
+		return err
+	}
+	ierr := invoker(ctx, method, req, reply, cc, opts...)
+	var em message
+	if ierr != nil {
+		em = message{err: ierr}
+	} else {
+		toWriteResp, berr := r.applyBeforeWrite(method, reply.(proto.Message))
+		if berr != nil {
+			return berr
+		}
+		em = message{msg: toWriteResp}
+	}
+	if _, werr := r.writeEntry(&entry{kind: pb.Entry_RESPONSE, msg: em, refIndex: idx}); werr != nil {
+		return werr
+	}
+	return ierr
+}
+
+func (r *Recorder) interceptStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if strings.HasPrefix(method, reflectionServiceName) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	r.ensureReflection(cc)
+	idx, err := r.writeEntry(&entry{kind: pb.Entry_CREATE_STREAM, method: method})
+	if err != nil {
+		return nil, err
+	}
+	s, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &recClientStream{ClientStream: s, r: r, method: method, refIndex: idx}, nil
+}
+
+// recClientStream wraps a grpc.ClientStream, recording every message sent
+// and received on it.
+type recClientStream struct {
+	grpc.ClientStream
+	r        *Recorder
+	method   string
+	refIndex int32
+}
+
+func (s *recClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return err
+	}
+	toWrite, berr := s.r.applyBeforeWrite(s.method, msg)
+	if berr != nil {
+		if err == nil {
+			err = berr
+		}
+		return err
+	}
+	if _, werr := s.r.writeEntry(&entry{kind: pb.Entry_SEND, msg: message{msg: toWrite}, refIndex: s.refIndex}); werr != nil && err == nil {
+		err = werr
+	}
+	return err
+}
+
+func (s *recClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	var em message
+	if err != nil {
+		em = message{err: err}
+	} else {
+		msg, ok := m.(proto.Message)
+		if !ok {
+			return err
+		}
+		toWrite, berr := s.r.applyBeforeWrite(s.method, msg)
+		if berr != nil {
+			return berr
+		}
+		em = message{msg: toWrite}
+	}
+	if _, werr := s.r.writeEntry(&entry{kind: pb.Entry_RECV, msg: em, refIndex: s.refIndex}); werr != nil {
+		if err == nil || err == io.EOF {
+			return werr
+		}
+	}
+	return err
+}
+
+// A Replayer replays RPCs from a recording made by a Recorder.
+type Replayer struct {
+	mu                sync.Mutex
+	opts              ReplayerOptions
+	initial           []byte
+	fileDescriptorSet []byte
+	unary             map[string][]*unaryCall
+	streams           map[string][]*streamRecord
+	attempts          map[string]int // cumulative per-method attempt count, for FaultInjector/ExpectRetries; never reset
+	srv               *grpc.Server
+	lis               *bufconn.Listener
+	closeFn           func() error
+}
+
+// ReplayerOptions configures a Replayer.
+type ReplayerOptions struct {
+	// BeforeMatch, if non-nil, is called with the method and the recorded
+	// request message immediately before that recorded call is used to
+	// answer an incoming RPC. It may mutate the message in place, but the
+	// mutation does not affect which recorded call is chosen: unary calls
+	// to a method are answered in recorded order without comparing request
+	// contents, and streams are matched on the first sent message's bytes
+	// as read from the recording, before BeforeMatch runs.
+	// Returning an error fails the incoming RPC with that error.
+	BeforeMatch func(method string, msg proto.Message) error
+
+	// FaultInjector, if non-nil, is consulted before each incoming call is
+	// matched against the recording, and can inject a synthetic error or
+	// delay instead — see FaultInjector and NewBackoffFaultInjector. This
+	// lets a test exercise a client's retry and backoff logic without
+	// altering the recorded entries. It takes precedence over
+	// ExpectRetries.
+	FaultInjector FaultInjector
+
+	// ExpectRetries, if positive and FaultInjector is nil, tolerates a
+	// client that retries: the first ExpectRetries calls to each method
+	// fail with codes.Unavailable, and only the next one is matched
+	// against the recording. This lets a recording made without retries
+	// validate a client that performs them.
+	ExpectRetries int
+}
+
+type unaryCall struct {
+	reqMsg   proto.Message
+	response message
+}
+
+type streamAction struct {
+	kind pb.Entry_Kind // SEND or RECV
+	msg  message
+}
+
+type streamRecord struct {
+	method    string
+	firstSend []byte // raw bytes of the first SEND action, used to match out-of-order streams
+	actions   []streamAction
+}
+
+// NewReplayer creates a Replayer that reads the recording in filename.
+func NewReplayer(filename string, opts *ReplayerOptions) (*Replayer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rep, err := NewReplayerReader(f, opts)
+	if err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// NewReplayerReader creates a Replayer that reads the recording from r.
+func NewReplayerReader(r io.Reader, opts *ReplayerOptions) (*Replayer, error) {
+	if opts == nil {
+		opts = &ReplayerOptions{}
+	}
+	rr, err := newReader(r, "")
+	if err != nil {
+		return nil, err
+	}
+	initial, err := rr.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	var order []*entry
+	for {
+		e, err := rr.readEntry()
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			break
+		}
+		order = append(order, e)
+	}
+	rep := &Replayer{
+		opts:     *opts,
+		initial:  initial,
+		unary:    map[string][]*unaryCall{},
+		streams:  map[string][]*streamRecord{},
+		attempts: map[string]int{},
+	}
+	if err := rep.build(order); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+func (rep *Replayer) build(order []*entry) error {
+	responses := map[int32]*entry{}
+	actionsByRef := map[int32][]*entry{}
+	for _, e := range order {
+		switch e.kind {
+		case pb.Entry_RESPONSE:
+			responses[e.refIndex] = e
+		case pb.Entry_SEND, pb.Entry_RECV:
+			actionsByRef[e.refIndex] = append(actionsByRef[e.refIndex], e)
+		}
+	}
+	for i, e := range order {
+		idx := int32(i + 1)
+		switch e.kind {
+		case pb.Entry_FILE_DESCRIPTOR_SET:
+			b, err := proto.Marshal(e.msg.msg)
+			if err != nil {
+				return err
+			}
+			rep.fileDescriptorSet = b
+		case pb.Entry_REQUEST:
+			resp := responses[idx]
+			if resp == nil {
+				return fmt.Errorf("grpcreplay: no response recorded for request #%d (%s)", idx, e.method)
+			}
+			rep.unary[e.method] = append(rep.unary[e.method], &unaryCall{reqMsg: e.msg.msg, response: resp.msg})
+		case pb.Entry_CREATE_STREAM:
+			sr := &streamRecord{method: e.method}
+			for _, a := range actionsByRef[idx] {
+				if a.kind == pb.Entry_SEND && sr.firstSend == nil && a.msg.msg != nil {
+					b, err := proto.Marshal(a.msg.msg)
+					if err != nil {
+						return err
+					}
+					sr.firstSend = b
+				}
+				sr.actions = append(sr.actions, streamAction{kind: a.kind, msg: a.msg})
+			}
+			rep.streams[e.method] = append(rep.streams[e.method], sr)
+		}
+	}
+	return nil
+}
+
+// Initial returns the initial state saved with the recording.
+func (rep *Replayer) Initial() []byte {
+	return rep.initial
+}
+
+// Connection returns a connection to the Replayer, which behaves like a
+// real gRPC server replaying the recorded calls. A Replayer already handed
+// to Serve cannot also be used with Connection; create a second Replayer
+// from the same recording instead.
+func (rep *Replayer) Connection() (*grpc.ClientConn, error) {
+	rep.mu.Lock()
+	if rep.srv != nil && rep.lis == nil {
+		rep.mu.Unlock()
+		return nil, fmt.Errorf("grpcreplay: Replayer is already serving via Serve; Connection and Serve cannot be used on the same Replayer")
+	}
+	if rep.srv == nil {
+		rep.start()
+	}
+	rep.mu.Unlock()
+	return grpc.Dial("replayer",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return rep.lis.DialContext(ctx)
+		}),
+		grpc.WithInsecure())
+}
+
+func (rep *Replayer) start() {
+	rep.lis = bufconn.Listen(bufSize)
+	rep.srv = rep.newServer()
+	go rep.srv.Serve(rep.lis)
+}
+
+// newServer builds the grpc.Server shared by Connection's in-process
+// listener and Serve's real one: it replays recorded calls through
+// handleStream, passing messages through as raw bytes since it knows
+// nothing of the recorded service's proto types, and serves reflection
+// from whatever FileDescriptorSet the recording embeds.
+func (rep *Replayer) newServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnknownServiceHandler(rep.handleStream),
+		grpc.ForceServerCodec(rawCodec{}),
+	)
+	// Best-effort: a recording made without CaptureReflection simply
+	// leaves this server without reflection support.
+	_ = rep.RegisterReflection(srv)
+	return srv
+}
+
+// Serve runs the Replayer as a real gRPC server on lis, replaying
+// recorded calls exactly as Connection's in-process server does, until
+// lis is closed or Close is called. This lets clients outside the Go
+// process — including ones written in other languages — exercise a
+// recording over the network; wrap lis with tls.NewListener first for
+// TLS termination. A Replayer can only be served once, by either Serve or
+// Connection, never both; create a second Replayer from the same
+// recording if both are needed.
+func (rep *Replayer) Serve(lis net.Listener) error {
+	rep.mu.Lock()
+	if rep.srv != nil {
+		rep.mu.Unlock()
+		return fmt.Errorf("grpcreplay: Replayer is already serving")
+	}
+	rep.srv = rep.newServer()
+	srv := rep.srv
+	rep.mu.Unlock()
+	return srv.Serve(lis)
+}
+
+// RegisterReflection registers the gRPC server reflection service
+// (grpc.reflection.v1alpha.ServerReflection) on s, answering from the
+// FileDescriptorSet embedded in the recording rep was built from. This
+// lets a server built around rep — for example one wired up by a caller
+// that embeds rep's handling into its own grpc.Server — be introspected
+// by tools like grpcurl with no compiled stubs for the recorded service.
+//
+// Connection already does this for the in-process server it creates.
+func (rep *Replayer) RegisterReflection(s *grpc.Server) error {
+	rs, err := newReplayReflectionServer(rep.fileDescriptorSet)
+	if err != nil {
+		return err
+	}
+	rpb.RegisterServerReflectionServer(s, rs)
+	return nil
+}
+
+// Close shuts down the Replayer.
+func (rep *Replayer) Close() error {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if rep.srv != nil {
+		rep.srv.Stop()
+	}
+	return nil
+}
+
+// rawFrame carries an undecoded protocol buffer payload, letting the
+// Replayer serve RPCs without knowing the service's proto types.
+type rawFrame struct {
+	payload []byte
+}
+
+// rawCodec is a grpc codec that treats messages as opaque bytes instead of
+// encoding/decoding proto.Messages, for the replayed service whose proto
+// types the Replayer doesn't know. newServer installs it with
+// grpc.ForceServerCodec, which makes it the codec for every service
+// registered on that grpc.Server, such as reflection (see
+// RegisterReflection); for those, it falls back to ordinary proto
+// marshaling.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	if f, ok := v.(*rawFrame); ok {
+		return f.payload, nil
+	}
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+	return nil, fmt.Errorf("grpcreplay: rawCodec cannot marshal %T", v)
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case *rawFrame:
+		m.payload = append([]byte(nil), data...)
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("grpcreplay: rawCodec cannot unmarshal into %T", v)
+}
+
+func (rawCodec) Name() string { return "proto" }
+
+func (rep *Replayer) handleStream(srv interface{}, ss grpc.ServerStream) error {
+	method, ok := grpc.MethodFromServerStream(ss)
+	if !ok {
+		return gstatus.Error(codes.Internal, "grpcreplay: could not determine method")
+	}
+
+	if rep.opts.FaultInjector != nil || rep.opts.ExpectRetries > 0 {
+		if err := rep.injectFault(method); err != nil {
+			return err
+		}
+	}
+
+	rep.mu.Lock()
+	if calls := rep.unary[method]; len(calls) > 0 {
+		call := calls[0]
+		rep.unary[method] = calls[1:]
+		rep.mu.Unlock()
+		return rep.replayUnary(method, ss, call)
+	}
+	streams := rep.streams[method]
+	if len(streams) == 0 {
+		rep.mu.Unlock()
+		return gstatus.Errorf(codes.NotFound, "grpcreplay: no recorded call for method %q", method)
+	}
+	sr, remaining, consumed := matchStream(ss, streams)
+	rep.streams[method] = remaining
+	if err := rep.applyBeforeMatch(method, sr); err != nil {
+		rep.mu.Unlock()
+		return err
+	}
+	rep.mu.Unlock()
+	return replayStream(ss, sr, consumed)
+}
+
+// injectFault consults rep.opts.FaultInjector (or the ExpectRetries
+// equivalent) for the next attempt at method, sleeping and/or returning
+// its synthesized error as instructed. A nil return means the call
+// should proceed to be matched against the recording as usual.
+//
+// attempt counts every call ever made to method, successful or not, and
+// is never reset: with an injector that lets every attempt at or above
+// some threshold through (as NewBackoffFaultInjector's and the
+// ExpectRetries injector do), once a method has failed its quota of
+// attempts, later, independent calls to that method succeed on their first
+// attempt instead of paying for the same quota all over again.
+func (rep *Replayer) injectFault(method string) error {
+	injector := rep.opts.FaultInjector
+	if injector == nil {
+		injector = expectRetriesInjector(rep.opts.ExpectRetries)
+	}
+
+	rep.mu.Lock()
+	attempt := rep.attempts[method]
+	rep.attempts[method] = attempt + 1
+	rep.mu.Unlock()
+
+	delay, st := injector(method, attempt)
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if st != nil {
+		return st.Err()
+	}
+	return nil
+}
+
+func (rep *Replayer) applyBeforeMatch(method string, sr *streamRecord) error {
+	if rep.opts.BeforeMatch == nil {
+		return nil
+	}
+	for _, a := range sr.actions {
+		if a.kind == pb.Entry_SEND && a.msg.msg != nil {
+			return rep.opts.BeforeMatch(method, a.msg.msg)
+		}
+	}
+	return nil
+}
+
+func (rep *Replayer) replayUnary(method string, ss grpc.ServerStream, call *unaryCall) error {
+	var raw rawFrame
+	if err := ss.RecvMsg(&raw); err != nil {
+		return err
+	}
+	if rep.opts.BeforeMatch != nil && call.reqMsg != nil {
+		if err := rep.opts.BeforeMatch(method, call.reqMsg); err != nil {
+			return err
+		}
+	}
+	if call.response.err != nil {
+		return call.response.err
+	}
+	b, err := proto.Marshal(call.response.msg)
+	if err != nil {
+		return err
+	}
+	return ss.SendMsg(&rawFrame{payload: b})
+}
+
+// matchStream picks the streamRecord among streams that corresponds to the
+// call in progress on ss, by comparing the bytes of the first message sent
+// on the stream, if any, against each candidate's firstSend. This lets
+// streams of the same method be replayed correctly even if the client
+// issues them in a different order than they were recorded (see
+// TestOutOfOrderStreamReplay). It returns the chosen record, the remaining
+// unmatched records for method, and the number of actions on sr that have
+// already been consumed from ss.
+func matchStream(ss grpc.ServerStream, streams []*streamRecord) (sr *streamRecord, remaining []*streamRecord, consumed int) {
+	if len(streams) == 1 {
+		return streams[0], nil, 0
+	}
+	var raw rawFrame
+	if err := ss.RecvMsg(&raw); err != nil {
+		return streams[0], streams[1:], 0
+	}
+	for i, cand := range streams {
+		if cand.firstSend != nil && bytes.Equal(cand.firstSend, raw.payload) {
+			rem := make([]*streamRecord, 0, len(streams)-1)
+			rem = append(rem, streams[:i]...)
+			rem = append(rem, streams[i+1:]...)
+			return cand, rem, 1
+		}
+	}
+	return streams[0], streams[1:], 1
+}
+
+func replayStream(ss grpc.ServerStream, sr *streamRecord, consumed int) error {
+	for _, a := range sr.actions[consumed:] {
+		switch a.kind {
+		case pb.Entry_SEND:
+			var raw rawFrame
+			if err := ss.RecvMsg(&raw); err != nil {
+				return err
+			}
+		case pb.Entry_RECV:
+			if a.msg.err != nil {
+				if a.msg.err == io.EOF {
+					return nil
+				}
+				return a.msg.err
+			}
+			b, err := proto.Marshal(a.msg.msg)
+			if err != nil {
+				return err
+			}
+			if err := ss.SendMsg(&rawFrame{payload: b}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
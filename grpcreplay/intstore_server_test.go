@@ -0,0 +1,127 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	ipb "github.com/google/go-replayers/grpcreplay/proto/intstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// intStoreServer is a trivial, real implementation of the IntStore service
+// used to exercise the Recorder and Replayer against live and replayed
+// traffic.
+type intStoreServer struct {
+	ipb.UnimplementedIntStoreServer
+
+	Addr string
+
+	mu    sync.Mutex
+	items map[string]int32
+	srv   *grpc.Server
+}
+
+func newIntStoreServer() *intStoreServer {
+	s := &intStoreServer{items: map[string]int32{}}
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		panic(fmt.Sprintf("grpcreplay: intStoreServer listen: %v", err))
+	}
+	s.Addr = lis.Addr().String()
+	s.srv = grpc.NewServer()
+	ipb.RegisterIntStoreServer(s.srv, s)
+	reflection.Register(s.srv) // lets CaptureReflection have something to capture
+	go s.srv.Serve(lis)
+	return s
+}
+
+func (s *intStoreServer) stop() {
+	s.srv.Stop()
+}
+
+func (s *intStoreServer) Set(ctx context.Context, item *ipb.Item) (*ipb.SetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.items[item.Name]
+	s.items[item.Name] = item.Value
+	return &ipb.SetResponse{PrevValue: prev}, nil
+}
+
+func (s *intStoreServer) Get(ctx context.Context, req *ipb.GetRequest) (*ipb.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.items[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%q", req.Name)
+	}
+	return &ipb.Item{Name: req.Name, Value: v}, nil
+}
+
+func (s *intStoreServer) ListItems(req *ipb.ListItemsRequest, stream ipb.IntStore_ListItemsServer) error {
+	s.mu.Lock()
+	items := make([]*ipb.Item, 0, len(s.items))
+	for name, v := range s.items {
+		if v > req.GreaterThan {
+			items = append(items, &ipb.Item{Name: name, Value: v})
+		}
+	}
+	s.mu.Unlock()
+	for _, item := range items {
+		if err := stream.Send(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *intStoreServer) SetStream(stream ipb.IntStore_SetStreamServer) error {
+	var count int32
+	for {
+		item, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&ipb.Summary{Count: count})
+		}
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.items[item.Name] = item.Value
+		s.mu.Unlock()
+		count++
+	}
+}
+
+func (s *intStoreServer) StreamChat(stream ipb.IntStore_StreamChatServer) error {
+	for {
+		item, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(item); err != nil {
+			return err
+		}
+	}
+}
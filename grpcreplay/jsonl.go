@@ -0,0 +1,205 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/google/go-replayers/grpcreplay/proto/grpcreplay"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// jsonlMagic begins the header of a file in the jsonl format.
+const jsonlMagic = binaryMagic + "-jsonl"
+
+func init() {
+	RegisterCodec("jsonl", jsonlCodec{})
+}
+
+// jsonlCodec implements Codec with a format meant for tools like jq: one
+// JSON object per line, with messages rendered by protojson (so they read
+// as ordinary, @type-tagged JSON) rather than as opaque base64 blobs.
+type jsonlCodec struct{}
+
+func (jsonlCodec) Magic() []byte { return []byte(jsonlMagic) }
+
+// jsonlHeader is the single JSON line that begins a jsonl recording, right
+// after the magic line. encoding/json base64-encodes Initial for us.
+type jsonlHeader struct {
+	Initial []byte `json:"initial,omitempty"`
+}
+
+func (jsonlCodec) WriteHeader(w io.Writer, initial []byte) error {
+	if _, err := fmt.Fprintf(w, "%s\n", jsonlMagic); err != nil {
+		return err
+	}
+	b, err := json.Marshal(jsonlHeader{Initial: initial})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+func (jsonlCodec) ReadHeader(r io.Reader) ([]byte, error) {
+	br := asBufReader(r)
+	line, err := readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	if line != jsonlMagic {
+		return nil, fmt.Errorf("grpcreplay: bad jsonl magic %q", line)
+	}
+	line, err = readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	var h jsonlHeader
+	if err := json.Unmarshal([]byte(line), &h); err != nil {
+		return nil, err
+	}
+	return h.Initial, nil
+}
+
+// jsonlEntry is the JSON shape of one entry line. At most one of Message
+// or Error is set, mirroring pb.Entry's is_error flag; a CREATE_STREAM
+// entry has neither.
+type jsonlEntry struct {
+	Kind     string          `json:"kind"`
+	Method   string          `json:"method,omitempty"`
+	RefIndex int32           `json:"refIndex,omitempty"`
+	Message  json.RawMessage `json:"message,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func (jsonlCodec) WriteEntry(w io.Writer, pe *pb.Entry) error {
+	je := jsonlEntry{Kind: pe.GetKind().String(), Method: pe.GetMethod(), RefIndex: pe.GetRefIndex()}
+	if pe.GetIsError() {
+		err, uerr := unmarshalErr(pe.GetMessage())
+		if uerr != nil {
+			return uerr
+		}
+		je.Error = errText(err)
+	} else if len(pe.GetMessage()) > 0 {
+		// A CREATE_STREAM entry has neither a message nor an error; leave
+		// je.Message unset for it rather than feeding its empty payload to
+		// unmarshalAny, which fails on it since it carries no type URL.
+		msg, uerr := unmarshalAny(pe.GetMessage())
+		if uerr != nil {
+			return uerr
+		}
+		any, aerr := anypb.New(msg)
+		if aerr != nil {
+			return aerr
+		}
+		b, merr := protojson.Marshal(any)
+		if merr != nil {
+			return merr
+		}
+		je.Message = b
+	}
+	b, err := json.Marshal(je)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+func (jsonlCodec) ReadEntry(r io.Reader) (*pb.Entry, error) {
+	br := asBufReader(r)
+	line, err := readTextLine(br)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, io.EOF
+	}
+	var je jsonlEntry
+	if err := json.Unmarshal([]byte(line), &je); err != nil {
+		return nil, err
+	}
+	kind, ok := pb.Entry_Kind_value[je.Kind]
+	if !ok {
+		return nil, fmt.Errorf("grpcreplay: unknown entry kind %q", je.Kind)
+	}
+	pe := &pb.Entry{Kind: pb.Entry_Kind(kind), Method: je.Method, RefIndex: je.RefIndex}
+	if je.Error != "" {
+		pe.IsError = true
+		pe.Message = marshalErr(errFromText(je.Error))
+		return pe, nil
+	}
+	if len(je.Message) == 0 {
+		// A CREATE_STREAM entry, written with neither Message nor Error set.
+		return pe, nil
+	}
+	var any anypb.Any
+	if err := protojson.Unmarshal(je.Message, &any); err != nil {
+		return nil, err
+	}
+	msg, err := any.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalAny(msg)
+	if err != nil {
+		return nil, err
+	}
+	pe.Message = data
+	return pe, nil
+}
+
+// codeByName maps a codes.Code's String() form back to the Code, for
+// parsing errText's output.
+var codeByName = func() map[string]codes.Code {
+	m := map[string]codes.Code{}
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+	return m
+}()
+
+// errText renders err (a gRPC status error, or io.EOF) as stable,
+// round-trippable text: "EOF", or "<CodeName>: <message>".
+func errText(err error) string {
+	if err == io.EOF {
+		return eofSentinel
+	}
+	st, _ := gstatus.FromError(err)
+	return fmt.Sprintf("%s: %s", st.Code(), st.Message())
+}
+
+// errFromText reverses errText.
+func errFromText(s string) error {
+	if s == eofSentinel {
+		return io.EOF
+	}
+	name, msg := s, ""
+	if i := strings.Index(s, ": "); i >= 0 {
+		name, msg = s[:i], s[i+2:]
+	}
+	code, ok := codeByName[name]
+	if !ok {
+		code = codes.Unknown
+		msg = s
+	}
+	return gstatus.New(code, msg).Err()
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rpb "github.com/google/go-replayers/grpcreplay/proto/grpcreplay"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewReaderJSONL(t *testing.T) {
+	r := strings.NewReader(jsonlMagic)
+	rr, err := newReader(r, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reflect.TypeOf(rr), reflect.TypeOf(&codecReader{}); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestEntryIOJSONL(t *testing.T) {
+	for i, want := range []*entry{
+		{
+			kind:     rpb.Entry_REQUEST,
+			method:   "method",
+			msg:      message{msg: &rpb.Entry{}},
+			refIndex: 7,
+		},
+		{
+			kind:     rpb.Entry_RESPONSE,
+			method:   "method",
+			msg:      message{err: status.Error(codes.NotFound, "not found")},
+			refIndex: 8,
+		},
+		{
+			kind:     rpb.Entry_RECV,
+			method:   "method",
+			msg:      message{err: io.EOF},
+			refIndex: 3,
+		},
+	} {
+		buf := &bytes.Buffer{}
+		w := &codecWriter{c: jsonlCodec{}, w: buf}
+		r := &codecReader{c: jsonlCodec{}, r: buf}
+		if err := w.writeEntry(want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.readEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.equal(want) {
+			t.Errorf("#%d: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRecordJSONL(t *testing.T) {
+	buf := recordJSONL(t, testService)
+	r, err := newReader(buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotIstate, err := r.readHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(gotIstate, initialState) {
+		t.Fatalf("got %v, want %v", gotIstate, initialState)
+	}
+}
+
+func TestReplayJSONL(t *testing.T) {
+	buf := recordJSONL(t, testService)
+	replay(t, buf, testService)
+}
+
+// recordJSONL is record (grpcreplay_test.go) with the jsonl format forced,
+// since record only knows how to select between the built-in binary and
+// text formats.
+func recordJSONL(t *testing.T, run func(*testing.T, *grpc.ClientConn)) *bytes.Buffer {
+	srv := newIntStoreServer()
+	defer srv.stop()
+
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorderWriter(buf, &RecorderOptions{Initial: initialState, Format: "jsonl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.Dial(srv.Addr,
+		append([]grpc.DialOption{grpc.WithInsecure()}, rec.DialOptions()...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	run(t, conn)
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// replayReflectionServer implements the gRPC server reflection service
+// directly from a recording's embedded FileDescriptorSet, rather than from
+// a grpc.Server's own registered services (which a Replayer's in-process
+// server has none of; it answers everything through UnknownServiceHandler).
+type replayReflectionServer struct {
+	rpb.UnimplementedServerReflectionServer
+
+	files    map[string]*descriptorpb.FileDescriptorProto // by file name
+	services map[string]string                            // fully-qualified service name -> file name
+}
+
+func newReplayReflectionServer(fileDescriptorSet []byte) (*replayReflectionServer, error) {
+	s := &replayReflectionServer{
+		files:    map[string]*descriptorpb.FileDescriptorProto{},
+		services: map[string]string{},
+	}
+	if len(fileDescriptorSet) == 0 {
+		return s, nil
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(fileDescriptorSet, &set); err != nil {
+		return nil, err
+	}
+	for _, fd := range set.GetFile() {
+		s.files[fd.GetName()] = fd
+		for _, svc := range fd.GetService() {
+			s.services[fullServiceName(fd, svc)] = fd.GetName()
+		}
+	}
+	return s, nil
+}
+
+func fullServiceName(fd *descriptorpb.FileDescriptorProto, svc *descriptorpb.ServiceDescriptorProto) string {
+	if fd.GetPackage() == "" {
+		return svc.GetName()
+	}
+	return fd.GetPackage() + "." + svc.GetName()
+}
+
+// ServerReflectionInfo implements the ServerReflection service by
+// answering ListServices, FileContainingSymbol and FileByFilename requests
+// from the descriptors captured at record time. It does not resolve
+// transitive imports beyond what gRPC reflection itself returned then.
+func (s *replayReflectionServer) ServerReflectionInfo(stream rpb.ServerReflection_ServerReflectionInfoServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		resp := &rpb.ServerReflectionResponse{
+			ValidHost:       req.GetHost(),
+			OriginalRequest: req,
+		}
+		switch mr := req.MessageRequest.(type) {
+		case *rpb.ServerReflectionRequest_ListServices:
+			resp.MessageResponse = &rpb.ServerReflectionResponse_ListServicesResponse{
+				ListServicesResponse: &rpb.ListServiceResponse{Service: s.listServices()},
+			}
+		case *rpb.ServerReflectionRequest_FileContainingSymbol:
+			fd := s.fileForSymbol(mr.FileContainingSymbol)
+			if fd == nil {
+				resp.MessageResponse = errorResponse(codes.NotFound, fmt.Errorf("symbol not found: %s", mr.FileContainingSymbol))
+				break
+			}
+			fdResp, err := s.fileDescriptorResponse(fd)
+			if err != nil {
+				return err
+			}
+			resp.MessageResponse = fdResp
+		case *rpb.ServerReflectionRequest_FileByFilename:
+			fd, ok := s.files[mr.FileByFilename]
+			if !ok {
+				resp.MessageResponse = errorResponse(codes.NotFound, fmt.Errorf("file not found: %s", mr.FileByFilename))
+				break
+			}
+			fdResp, err := s.fileDescriptorResponse(fd)
+			if err != nil {
+				return err
+			}
+			resp.MessageResponse = fdResp
+		default:
+			resp.MessageResponse = errorResponse(codes.Unimplemented, fmt.Errorf("unsupported reflection request %T", mr))
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *replayReflectionServer) listServices() []*rpb.ServiceResponse {
+	var svcs []*rpb.ServiceResponse
+	for name := range s.services {
+		svcs = append(svcs, &rpb.ServiceResponse{Name: name})
+	}
+	return svcs
+}
+
+// fileForSymbol returns the file descriptor containing the service or
+// method named symbol (a fully qualified name), or nil if none was
+// captured.
+func (s *replayReflectionServer) fileForSymbol(symbol string) *descriptorpb.FileDescriptorProto {
+	for svcName, fileName := range s.services {
+		if symbol == svcName || strings.HasPrefix(symbol, svcName+".") {
+			return s.files[fileName]
+		}
+	}
+	return nil
+}
+
+func (s *replayReflectionServer) fileDescriptorResponse(fd *descriptorpb.FileDescriptorProto) (*rpb.ServerReflectionResponse_FileDescriptorResponse, error) {
+	b, err := proto.Marshal(fd)
+	if err != nil {
+		return nil, err
+	}
+	return &rpb.ServerReflectionResponse_FileDescriptorResponse{
+		FileDescriptorResponse: &rpb.FileDescriptorResponse{FileDescriptorProto: [][]byte{b}},
+	}, nil
+}
+
+func errorResponse(code codes.Code, err error) *rpb.ServerReflectionResponse_ErrorResponse {
+	return &rpb.ServerReflectionResponse_ErrorResponse{
+		ErrorResponse: &rpb.ErrorResponse{
+			ErrorCode:    int32(code),
+			ErrorMessage: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	ipb "github.com/google/go-replayers/grpcreplay/proto/intstore"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestNewReplayReflectionServer(t *testing.T) {
+	fdSet := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("greeter.proto"),
+				Package: proto.String("greeter"),
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{Name: proto.String("Greeter")},
+				},
+			},
+		},
+	}
+	b, err := proto.Marshal(fdSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newReplayReflectionServer(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(s.listServices()), 1; got != want {
+		t.Fatalf("got %d services, want %d", got, want)
+	}
+	if fd := s.fileForSymbol("greeter.Greeter"); fd == nil || fd.GetName() != "greeter.proto" {
+		t.Errorf("fileForSymbol(%q) = %v, want greeter.proto", "greeter.Greeter", fd)
+	}
+	if fd := s.fileForSymbol("greeter.Greeter.SayHello"); fd == nil {
+		t.Errorf("fileForSymbol did not match a method of a captured service")
+	}
+	if fd := s.fileForSymbol("nope.Nope"); fd != nil {
+		t.Errorf("fileForSymbol(%q) = %v, want nil", "nope.Nope", fd)
+	}
+}
+
+func TestNewReplayReflectionServerEmpty(t *testing.T) {
+	s, err := newReplayReflectionServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.listServices(); len(got) != 0 {
+		t.Errorf("got %v, want no services", got)
+	}
+}
+
+// TestCaptureReflection records a live RPC with CaptureReflection enabled,
+// then checks that the resulting Replayer serves the target's own
+// reflection data back, without this test's process ever describing the
+// IntStore service by name.
+func TestCaptureReflection(t *testing.T) {
+	srv := newIntStoreServer()
+	defer srv.stop()
+
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorderWriter(buf, &RecorderOptions{Initial: initialState, CaptureReflection: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.Dial(srv.Addr,
+		append([]grpc.DialOption{grpc.WithInsecure()}, rec.DialOptions()...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ipb.NewIntStoreClient(conn)
+	if _, err := client.Set(context.Background(), &ipb.Item{Name: "a", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := NewReplayerReader(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rep.Close()
+	if len(rep.fileDescriptorSet) == 0 {
+		t.Fatal("CaptureReflection did not embed a FileDescriptorSet")
+	}
+
+	rconn, err := rep.Connection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rconn.Close()
+
+	stream, err := rpb.NewServerReflectionClient(rconn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "intstore.IntStore"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fdResp := resp.GetFileDescriptorResponse()
+	if fdResp == nil || len(fdResp.GetFileDescriptorProto()) == 0 {
+		t.Fatalf("got %v, want a FileDescriptorResponse for intstore.IntStore", resp)
+	}
+	var fd descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(fdResp.GetFileDescriptorProto()[0], &fd); err != nil {
+		t.Fatal(err)
+	}
+	if fd.GetPackage() != "intstore" {
+		t.Errorf("got package %q, want %q", fd.GetPackage(), "intstore")
+	}
+}
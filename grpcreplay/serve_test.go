@@ -0,0 +1,161 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcreplay
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestDetectFormat(t *testing.T) {
+	for _, format := range []string{"binary", "text", "jsonl"} {
+		t.Run(format, func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "grpcreplay")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec, err := NewRecorderWriter(f, &RecorderOptions{Initial: initialState, Format: format})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := rec.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Close(); err != nil {
+				t.Fatal(err)
+			}
+			got, err := DetectFormat(f.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != format {
+				t.Errorf("got %q, want %q", got, format)
+			}
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	buf := record(t, "binary", testService)
+	rep, err := NewReplayerReader(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rep.Close()
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go rep.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	testService(t, conn)
+}
+
+// TestServeConnectionMutualExclusion checks that a Replayer rejects being
+// handed to both Serve and Connection, in either order, rather than
+// panicking (Connection after Serve) or silently starting a second
+// server (Serve after Connection).
+func TestServeConnectionMutualExclusion(t *testing.T) {
+	t.Run("ServeThenConnection", func(t *testing.T) {
+		buf := record(t, "binary", testService)
+		rep, err := NewReplayerReader(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rep.Close()
+
+		lis, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go rep.Serve(lis)
+
+		// Block until the server is actually accepting connections, so
+		// rep.srv is guaranteed to be set before Connection is called.
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		if _, err := rep.Connection(); err == nil {
+			t.Error("Connection after Serve: got nil error, want one")
+		}
+	})
+
+	t.Run("ServeThenServe", func(t *testing.T) {
+		buf := record(t, "binary", testService)
+		rep, err := NewReplayerReader(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rep.Close()
+
+		lis, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go rep.Serve(lis)
+
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		lis2, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer lis2.Close()
+		if err := rep.Serve(lis2); err == nil {
+			t.Error("second Serve: got nil error, want one")
+		}
+	})
+
+	t.Run("ConnectionThenServe", func(t *testing.T) {
+		buf := record(t, "binary", testService)
+		rep, err := NewReplayerReader(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rep.Close()
+
+		conn, err := rep.Connection()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		lis, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer lis.Close()
+		if err := rep.Serve(lis); err == nil {
+			t.Error("Serve after Connection: got nil error, want one")
+		}
+	})
+}